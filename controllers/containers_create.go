@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/voidchef/devops/utils"
+)
+
+// CreateContainer handles POST /api/docker/containers, accepting a full
+// container spec (image, env, ports, mounts, restart policy, resource
+// limits) so stacks can be wired up entirely from the dashboard.
+func CreateContainer(c *gin.Context) {
+	var spec utils.ContainerSpec
+	if err := c.ShouldBindJSON(&spec); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	containerID, err := utils.CreateContainer(dockerClient(c), spec)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"containerID": containerID})
+}
@@ -12,9 +12,9 @@ import (
 )
 
 func GetContainers(c *gin.Context) {
-	containers, err := utils.ListContainers()
+	containers, err := utils.ListContainers(dockerClient(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -91,13 +91,13 @@ func GetStats(c *gin.Context) {
 		return
 	}
 
-	stats, err := utils.GetContainerStatsByID(containerID)
+	stats, err := utils.GetContainerStatsByID(dockerClient(c), containerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	cpuUsage := float64(stats.CPUStats.CPUUsage.TotalUsage) / float64(stats.CPUStats.SystemUsage) * 100
+	cpuUsage := utils.CPUPercentFromSnapshot(stats)
 	memoryUsage := float64(stats.MemoryStats.Usage) / (1024 * 1024)
 	memoryLimit := float64(stats.MemoryStats.Limit) / (1024 * 1024)
 	networkRxBytes := float64(stats.Networks["eth0"].RxBytes) / (1024 * 1024)
@@ -127,9 +127,9 @@ func StartContainer(c *gin.Context) {
 		return
 	}
 
-	err := utils.StartContainerByID(containerID)
+	err := utils.StartContainerByID(dockerClient(c), containerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -143,9 +143,9 @@ func StopContainer(c *gin.Context) {
 		return
 	}
 
-	err := utils.StopContainerByID(containerID)
+	err := utils.StopContainerByID(dockerClient(c), containerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -159,13 +159,13 @@ func UpdateContainer(c *gin.Context) {
 		return
 	}
 
-	err := utils.UpdateContainerByID(containerID)
+	result, err := utils.UpdateContainerByID(dockerClient(c), containerID, utils.UpdateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(statusForError(err), gin.H{"error": err.Error(), "result": result})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "success"})
+	c.JSON(http.StatusOK, gin.H{"message": "success", "result": result})
 }
 
 func DeleteContainer(c *gin.Context) {
@@ -175,9 +175,9 @@ func DeleteContainer(c *gin.Context) {
 		return
 	}
 
-	err := utils.DeleteContainerByID(containerID)
+	err := utils.DeleteContainerByID(dockerClient(c), containerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
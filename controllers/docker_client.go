@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	"github.com/docker/docker/client"
+	"github.com/gin-gonic/gin"
+	"github.com/voidchef/devops/middleware"
+)
+
+// dockerClient returns the *client.Client middleware.DockerHostMiddleware
+// resolved for this request.
+func dockerClient(c *gin.Context) *client.Client {
+	return c.MustGet(middleware.DockerHostKey).(*client.Client)
+}
+
+// dockerHostName returns the host name middleware.DockerHostMiddleware
+// resolved for this request.
+func dockerHostName(c *gin.Context) string {
+	return c.MustGet(middleware.DockerHostNameKey).(string)
+}
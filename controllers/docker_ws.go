@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/docker/docker/api/types"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/voidchef/devops/utils"
+)
+
+// upgrader is shared by every Docker WebSocket route. CheckOrigin is left
+// permissive since the dashboard is typically served from a different
+// origin than the API during local development.
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// GetContainerStatsWS streams per-container stats frames over a WebSocket,
+// one JSON message per sample, until the client disconnects or the
+// container stops.
+func GetContainerStatsWS(c *gin.Context) {
+	containerID := c.Param("containerID")
+	if containerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "containerID is empty"})
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	frames, unsubscribe, err := utils.DefaultStatsHub().Subscribe(dockerClient(c), dockerHostName(c), containerID)
+	if err != nil {
+		conn.WriteJSON(gin.H{"error": err.Error()})
+		return
+	}
+	defer unsubscribe()
+
+	var previous *types.StatsJSON
+	for frame := range frames {
+		current := frame
+		cpuPercent := utils.CPUPercent(previous, &current)
+		previous = &current
+
+		if err := conn.WriteJSON(gin.H{
+			"containerID": containerID,
+			"cpuPercent":  cpuPercent,
+			"memoryUsage": frame.MemoryStats.Usage,
+			"memoryLimit": frame.MemoryStats.Limit,
+			"networks":    frame.Networks,
+		}); err != nil {
+			return
+		}
+	}
+}
+
+// GetDockerEventsWS streams the daemon's event feed (container/image/network
+// lifecycle events) over a WebSocket so dashboards update live instead of
+// polling.
+func GetDockerEventsWS(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	messages, unsubscribe := utils.EventsHubFor(dockerHostName(c)).Subscribe(dockerClient(c))
+	defer unsubscribe()
+
+	for msg := range messages {
+		if err := conn.WriteJSON(msg); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/voidchef/devops/errdefs"
+)
+
+// statusForError walks err's chain looking for one of the errdefs marker
+// interfaces and returns the matching HTTP status, falling back to 500 when
+// none match.
+func statusForError(err error) int {
+	switch {
+	case errdefs.IsNotFound(err):
+		return http.StatusNotFound
+	case errdefs.IsConflict(err):
+		return http.StatusConflict
+	case errdefs.IsUnauthorized(err):
+		return http.StatusUnauthorized
+	case errdefs.IsInvalidParameter(err):
+		return http.StatusBadRequest
+	case errdefs.IsUnavailable(err):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// respondError responds with statusForError(err) and the error message.
+func respondError(c *gin.Context, err error) {
+	c.JSON(statusForError(err), gin.H{"error": err.Error()})
+}
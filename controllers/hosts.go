@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/voidchef/devops/models"
+	"github.com/voidchef/devops/utils"
+)
+
+// GetHosts lists every Docker host registered with the dashboard along with
+// its last health-check result.
+func GetHosts(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"hosts": utils.Registry().Statuses()})
+}
+
+// CreateHost persists a new Docker host and registers it with the live
+// Registry, so it's reachable via X-Docker-Host without a restart.
+func CreateHost(c *gin.Context) {
+	var req struct {
+		Name     string `json:"name" binding:"required"`
+		Address  string `json:"address" binding:"required"`
+		CertPath string `json:"certPath" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cfg := utils.DockerHostConfig{Name: req.Name, Address: req.Address, CertPath: req.CertPath}
+	if err := utils.Registry().Register(cfg); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	host := models.DockerHost{Name: req.Name, Address: req.Address, CertPath: req.CertPath}
+	saved, err := host.SaveHost()
+	if err != nil {
+		utils.Registry().Unregister(req.Name)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"host": saved})
+}
+
+// DeleteHost unregisters a Docker host and removes it from persistence.
+func DeleteHost(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is empty"})
+		return
+	}
+
+	if err := models.DeleteHostByName(name); err != nil {
+		respondError(c, err)
+		return
+	}
+	utils.Registry().Unregister(name)
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/gin-gonic/gin"
+	"github.com/voidchef/devops/utils"
+)
+
+func GetImages(c *gin.Context) {
+	images, err := utils.ListImages(dockerClient(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"imageList": images})
+}
+
+// PullImageSSE pulls `?ref=` from its registry and streams the daemon's
+// layer-by-layer progress to the client as Server-Sent Events, one JSON
+// message per event. Private registries are supported via the
+// X-Registry-Auth header (base64-encoded types.AuthConfig).
+func PullImageSSE(c *gin.Context) {
+	ref := c.Query("ref")
+	if ref == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ref is empty"})
+		return
+	}
+
+	registryAuth, err := utils.DecodeRegistryAuth(c.GetHeader("X-Registry-Auth"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	body, err := utils.PullImage(dockerClient(c), ref, registryAuth)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer body.Close()
+
+	streamJSONMessagesAsSSE(c, body)
+}
+
+// BuildImageSSE builds an image from an uploaded tar build context
+// (multipart field "context") and streams build progress as SSE. Tags are
+// passed as repeated `?tag=` query params.
+func BuildImageSSE(c *gin.Context) {
+	tags := c.QueryArray("tag")
+
+	file, _, err := c.Request.FormFile("context")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "context build tar is required"})
+		return
+	}
+	defer file.Close()
+
+	body, err := utils.BuildImage(dockerClient(c), file, types.ImageBuildOptions{
+		Tags:       tags,
+		Remove:     true,
+		Dockerfile: c.DefaultQuery("dockerfile", "Dockerfile"),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer body.Close()
+
+	streamJSONMessagesAsSSE(c, body)
+}
+
+// streamJSONMessagesAsSSE decodes the daemon's newline-delimited JSON
+// progress stream (the same format `docker pull`/`docker build` render in a
+// terminal) and relays each message as one SSE event named "progress".
+func streamJSONMessagesAsSSE(c *gin.Context, body io.Reader) {
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+
+	decoder := json.NewDecoder(body)
+	c.Stream(func(w io.Writer) bool {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			return false
+		}
+
+		c.SSEvent("progress", msg)
+		return true
+	})
+}
+
+func TagImage(c *gin.Context) {
+	var req struct {
+		Source string `json:"source" binding:"required"`
+		Target string `json:"target" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := utils.TagImage(dockerClient(c), req.Source, req.Target); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+func RemoveImage(c *gin.Context) {
+	imageID := c.Param("imageID")
+	if imageID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "imageID is empty"})
+		return
+	}
+
+	force := c.Query("force") == "true"
+
+	removed, err := utils.RemoveImage(dockerClient(c), imageID, force)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"removed": removed})
+}
+
+func PruneImages(c *gin.Context) {
+	report, err := utils.PruneImages(dockerClient(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/voidchef/devops/utils"
+)
+
+// wsLogWriter adapts a WebSocket connection to io.Writer so stdcopy.StdCopy
+// can demultiplex the Docker log stream straight onto the socket, tagging
+// each chunk with which stream it came from.
+type wsLogWriter struct {
+	conn   *websocket.Conn
+	stream string
+}
+
+func (w *wsLogWriter) Write(p []byte) (int, error) {
+	if err := w.conn.WriteJSON(gin.H{"stream": w.stream, "data": string(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// GetContainerLogs handles GET /api/docker/logs/:containerID, supporting
+// `follow`, `tail`, `since`, `until`, `timestamps`, and `stdout`/`stderr`
+// toggles. follow=true upgrades the connection to a WebSocket and streams
+// demultiplexed stdout/stderr frames as they arrive; otherwise the full
+// (tail-bounded) log is decoded and returned as JSON.
+func GetContainerLogs(c *gin.Context) {
+	containerID := c.Param("containerID")
+	if containerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "containerID is empty"})
+		return
+	}
+
+	follow := c.Query("follow") == "true"
+
+	opts := types.ContainerLogsOptions{
+		ShowStdout: c.DefaultQuery("stdout", "true") == "true",
+		ShowStderr: c.DefaultQuery("stderr", "true") == "true",
+		Since:      c.Query("since"),
+		Until:      c.Query("until"),
+		Timestamps: c.Query("timestamps") == "true",
+		Follow:     follow,
+		Tail:       c.DefaultQuery("tail", "all"),
+	}
+
+	logs, err := utils.GetContainerLogs(dockerClient(c), containerID, opts)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer logs.Close()
+
+	tty, err := utils.ContainerHasTTY(dockerClient(c), containerID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if follow {
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if tty {
+			io.Copy(&wsLogWriter{conn: conn, stream: "stdout"}, logs)
+			return
+		}
+
+		stdcopy.StdCopy(
+			&wsLogWriter{conn: conn, stream: "stdout"},
+			&wsLogWriter{conn: conn, stream: "stderr"},
+			logs,
+		)
+		return
+	}
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	if tty {
+		io.Copy(&stdoutBuf, logs)
+	} else {
+		stdcopy.StdCopy(&stdoutBuf, &stderrBuf, logs)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"stdout": stdoutBuf.String(), "stderr": stderrBuf.String()})
+}
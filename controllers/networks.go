@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/voidchef/devops/utils"
+)
+
+func GetNetworks(c *gin.Context) {
+	networks, err := utils.ListNetworks(dockerClient(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"networkList": networks})
+}
+
+func CreateNetwork(c *gin.Context) {
+	var req struct {
+		Name   string `json:"name" binding:"required"`
+		Driver string `json:"driver"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	networkID, err := utils.CreateNetwork(dockerClient(c), req.Name, req.Driver)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"networkID": networkID})
+}
+
+func DeleteNetwork(c *gin.Context) {
+	networkID := c.Param("networkID")
+	if networkID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "networkID is empty"})
+		return
+	}
+
+	if err := utils.RemoveNetwork(dockerClient(c), networkID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+func ConnectNetwork(c *gin.Context) {
+	networkID := c.Param("networkID")
+	if networkID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "networkID is empty"})
+		return
+	}
+
+	var req struct {
+		ContainerID string `json:"containerID" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := utils.ConnectNetwork(dockerClient(c), networkID, req.ContainerID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+func DisconnectNetwork(c *gin.Context) {
+	networkID := c.Param("networkID")
+	if networkID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "networkID is empty"})
+		return
+	}
+
+	var req struct {
+		ContainerID string `json:"containerID" binding:"required"`
+		Force       bool   `json:"force"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := utils.DisconnectNetwork(dockerClient(c), networkID, req.ContainerID, req.Force); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/voidchef/devops/utils"
+)
+
+func GetVolumes(c *gin.Context) {
+	volumes, err := utils.ListVolumes(dockerClient(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"volumeList": volumes})
+}
+
+func CreateVolume(c *gin.Context) {
+	var req struct {
+		Name   string            `json:"name" binding:"required"`
+		Driver string            `json:"driver"`
+		Labels map[string]string `json:"labels"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	volume, err := utils.CreateVolume(dockerClient(c), req.Name, req.Driver, req.Labels)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"volume": volume})
+}
+
+func DeleteVolume(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is empty"})
+		return
+	}
+
+	force := c.Query("force") == "true"
+
+	if err := utils.RemoveVolume(dockerClient(c), name, force); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
@@ -0,0 +1,29 @@
+package errdefs
+
+import (
+	dockererrdefs "github.com/docker/docker/errdefs"
+)
+
+// FromDockerError maps an error returned by the Docker SDK client onto this
+// package's taxonomy, using the SDK's own errdefs helpers (every docker
+// client error already implements them) as the source of truth.
+func FromDockerError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case dockererrdefs.IsNotFound(err):
+		return NotFound(err)
+	case dockererrdefs.IsConflict(err):
+		return Conflict(err)
+	case dockererrdefs.IsUnauthorized(err):
+		return Unauthorized(err)
+	case dockererrdefs.IsInvalidParameter(err):
+		return InvalidParameter(err)
+	case dockererrdefs.IsUnavailable(err), dockererrdefs.IsDeadline(err), dockererrdefs.IsCancelled(err):
+		return Unavailable(err)
+	default:
+		return err
+	}
+}
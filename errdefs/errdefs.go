@@ -0,0 +1,143 @@
+// Package errdefs defines a small taxonomy of typed errors so HTTP handlers
+// can map a failure to the right status code instead of always returning
+// 500. It mirrors the approach moby's own API layer uses internally.
+package errdefs
+
+import "errors"
+
+// ErrNotFound is implemented by errors representing "the requested object
+// does not exist" (maps to 404).
+type ErrNotFound interface {
+	NotFound() bool
+}
+
+// ErrConflict is implemented by errors representing a conflict with the
+// current state of the object (maps to 409).
+type ErrConflict interface {
+	Conflict() bool
+}
+
+// ErrUnauthorized is implemented by errors representing a failed or missing
+// credential (maps to 401).
+type ErrUnauthorized interface {
+	Unauthorized() bool
+}
+
+// ErrInvalidParameter is implemented by errors representing a bad request
+// (maps to 400).
+type ErrInvalidParameter interface {
+	InvalidParameter() bool
+}
+
+// ErrUnavailable is implemented by errors representing a dependency that is
+// temporarily unreachable, e.g. the Docker daemon (maps to 503).
+type ErrUnavailable interface {
+	Unavailable() bool
+}
+
+type wrapped struct {
+	error
+	cause error
+}
+
+func (w wrapped) Unwrap() error { return w.cause }
+
+type notFoundError wrapped
+
+func (e notFoundError) Error() string { return e.error.Error() }
+func (e notFoundError) Unwrap() error { return e.cause }
+func (notFoundError) NotFound() bool  { return true }
+
+type conflictError wrapped
+
+func (e conflictError) Error() string { return e.error.Error() }
+func (e conflictError) Unwrap() error { return e.cause }
+func (conflictError) Conflict() bool  { return true }
+
+type unauthorizedError wrapped
+
+func (e unauthorizedError) Error() string    { return e.error.Error() }
+func (e unauthorizedError) Unwrap() error    { return e.cause }
+func (unauthorizedError) Unauthorized() bool { return true }
+
+type invalidParameterError wrapped
+
+func (e invalidParameterError) Error() string        { return e.error.Error() }
+func (e invalidParameterError) Unwrap() error        { return e.cause }
+func (invalidParameterError) InvalidParameter() bool { return true }
+
+type unavailableError wrapped
+
+func (e unavailableError) Error() string   { return e.error.Error() }
+func (e unavailableError) Unwrap() error   { return e.cause }
+func (unavailableError) Unavailable() bool { return true }
+
+// NotFound wraps err so that errors.As/IsNotFound identifies it as a 404.
+func NotFound(err error) error {
+	if err == nil {
+		return nil
+	}
+	return notFoundError{error: err, cause: err}
+}
+
+// Conflict wraps err so that errors.As/IsConflict identifies it as a 409.
+func Conflict(err error) error {
+	if err == nil {
+		return nil
+	}
+	return conflictError{error: err, cause: err}
+}
+
+// Unauthorized wraps err so that errors.As/IsUnauthorized identifies it as a 401.
+func Unauthorized(err error) error {
+	if err == nil {
+		return nil
+	}
+	return unauthorizedError{error: err, cause: err}
+}
+
+// InvalidParameter wraps err so that errors.As/IsInvalidParameter identifies it as a 400.
+func InvalidParameter(err error) error {
+	if err == nil {
+		return nil
+	}
+	return invalidParameterError{error: err, cause: err}
+}
+
+// Unavailable wraps err so that errors.As/IsUnavailable identifies it as a 503.
+func Unavailable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return unavailableError{error: err, cause: err}
+}
+
+// IsNotFound reports whether err, or any error in its chain, is an ErrNotFound.
+func IsNotFound(err error) bool {
+	var e ErrNotFound
+	return errors.As(err, &e) && e.NotFound()
+}
+
+// IsConflict reports whether err, or any error in its chain, is an ErrConflict.
+func IsConflict(err error) bool {
+	var e ErrConflict
+	return errors.As(err, &e) && e.Conflict()
+}
+
+// IsUnauthorized reports whether err, or any error in its chain, is an ErrUnauthorized.
+func IsUnauthorized(err error) bool {
+	var e ErrUnauthorized
+	return errors.As(err, &e) && e.Unauthorized()
+}
+
+// IsInvalidParameter reports whether err, or any error in its chain, is an ErrInvalidParameter.
+func IsInvalidParameter(err error) bool {
+	var e ErrInvalidParameter
+	return errors.As(err, &e) && e.InvalidParameter()
+}
+
+// IsUnavailable reports whether err, or any error in its chain, is an ErrUnavailable.
+func IsUnavailable(err error) bool {
+	var e ErrUnavailable
+	return errors.As(err, &e) && e.Unavailable()
+}
@@ -1,8 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -12,6 +17,14 @@ import (
 	"github.com/voidchef/devops/utils"
 )
 
+// hostHealthCheckInterval controls how often Registry().StartHealthChecks
+// pings every registered Docker host.
+const hostHealthCheckInterval = 30 * time.Second
+
+// shutdownGracePeriod bounds how long in-flight HTTP requests are given to
+// finish once a shutdown signal arrives.
+const shutdownGracePeriod = 15 * time.Second
+
 func main() {
 	// Load .env file
 	err := godotenv.Load()
@@ -35,6 +48,20 @@ func main() {
 		fmt.Println("Docker Daemon Connected!")
 	}
 
+	// Re-register any Docker hosts added in a previous run.
+	if hosts, err := models.ListHosts(); err != nil {
+		fmt.Printf("Error loading saved docker hosts -> %s \n", err)
+	} else {
+		for _, host := range hosts {
+			cfg := utils.DockerHostConfig{Name: host.Name, Address: host.Address, CertPath: host.CertPath}
+			if err := utils.Registry().Register(cfg); err != nil {
+				fmt.Printf("Error registering docker host %s -> %s \n", host.Name, err)
+			}
+		}
+	}
+
+	utils.Registry().StartHealthChecks(hostHealthCheckInterval, nil)
+
 	router := gin.Default()
 
 	public := router.Group("/api")
@@ -47,6 +74,18 @@ func main() {
 	// Use the JwtAuthMiddleware middleware for private routes
 	private.Use(middleware.JwtAuthMiddleware())
 
+	// Docker host management. These routes manage the registry itself, so
+	// they run before DockerHostMiddleware resolves a client for a specific
+	// host.
+	private.GET("/hosts", controllers.GetHosts)
+	private.POST("/hosts", controllers.CreateHost)
+	private.DELETE("/hosts/:name", controllers.DeleteHost)
+
+	// Resolve the Docker host a request targets (X-Docker-Host header,
+	// defaulting to utils.DefaultHostName) before any docker resource route
+	// runs.
+	private.Use(middleware.DockerHostMiddleware())
+
 	// Docker routes
 	private.GET("/containers", controllers.GetContainers)
 	private.GET("/stats/:containerID", controllers.GetStats)
@@ -55,6 +94,101 @@ func main() {
 	private.POST("/updateContainer/:containerID", controllers.UpdateContainer)
 	private.DELETE("/deleteContainer/:containerID", controllers.DeleteContainer)
 
-	// Run the server
-	router.Run(":" + os.Getenv("PORT"))
+	// Docker streaming routes
+	private.GET("/events/ws", controllers.GetDockerEventsWS)
+	private.GET("/stats/:containerID/ws", controllers.GetContainerStatsWS)
+
+	// Docker image routes
+	private.GET("/images", controllers.GetImages)
+	private.GET("/images/pull", controllers.PullImageSSE)
+	private.POST("/images/build", controllers.BuildImageSSE)
+	private.POST("/images/tag", controllers.TagImage)
+	private.DELETE("/images/:imageID", controllers.RemoveImage)
+	private.POST("/images/prune", controllers.PruneImages)
+
+	// Docker logs route
+	private.GET("/logs/:containerID", controllers.GetContainerLogs)
+
+	// Docker container creation
+	private.POST("/containers", controllers.CreateContainer)
+
+	// Docker volume routes
+	private.GET("/volumes", controllers.GetVolumes)
+	private.POST("/volumes", controllers.CreateVolume)
+	private.DELETE("/volumes/:name", controllers.DeleteVolume)
+
+	// Docker network routes
+	private.GET("/networks", controllers.GetNetworks)
+	private.POST("/networks", controllers.CreateNetwork)
+	private.DELETE("/networks/:networkID", controllers.DeleteNetwork)
+	private.POST("/networks/:networkID/connect", controllers.ConnectNetwork)
+	private.POST("/networks/:networkID/disconnect", controllers.DisconnectNetwork)
+
+	srv := &http.Server{
+		Addr:    ":" + os.Getenv("PORT"),
+		Handler: router,
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("Error starting server -> %s \n", err)
+		}
+	}()
+
+	waitForShutdown(srv)
+}
+
+// waitForShutdown blocks until SIGINT, SIGTERM or SIGQUIT arrives, then
+// drains in-flight requests before tearing down the Docker clients and
+// database connection. A second signal is only counted (the shutdown
+// already under way continues) and a third forces the process to exit
+// outright, so a stuck shutdown never hangs the terminal. SIGQUIT with DEBUG set skips all of
+// that and exits immediately, for attaching a debugger to a stuck process.
+func waitForShutdown(srv *http.Server) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+
+	received := 0
+	shuttingDown := false
+
+	for s := range sig {
+		if s == syscall.SIGQUIT && os.Getenv("DEBUG") != "" {
+			fmt.Println("SIGQUIT received with DEBUG set, exiting immediately without cleanup")
+			os.Exit(1)
+		}
+
+		received++
+		if received >= 3 {
+			fmt.Println("Received shutdown signal a third time, forcing exit")
+			os.Exit(1)
+		}
+
+		if !shuttingDown {
+			shuttingDown = true
+			fmt.Printf("Received %s, shutting down gracefully (send twice more to force exit)\n", s)
+			go shutdown(srv)
+		}
+	}
+}
+
+// shutdown stops the HTTP server from accepting new connections, waits up
+// to shutdownGracePeriod for in-flight requests to finish, then cancels the
+// shared Docker context (so streaming stats/logs/pulls unwind) and closes
+// every registered Docker client and the database connection.
+func shutdown(srv *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownGracePeriod)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		fmt.Printf("Error shutting down server -> %s \n", err)
+	}
+
+	utils.CancelDockerContext()
+	utils.Registry().CloseAll()
+
+	if sqlDB, err := models.DB.DB(); err == nil {
+		sqlDB.Close()
+	}
+
+	os.Exit(0)
 }
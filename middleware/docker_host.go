@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/voidchef/devops/utils"
+)
+
+// DockerHostKey and DockerHostNameKey are the gin context keys
+// DockerHostMiddleware stores the resolved client and host name under.
+const (
+	DockerHostKey     = "dockerClient"
+	DockerHostNameKey = "dockerHostName"
+)
+
+// DockerHostMiddleware resolves which registered Docker daemon a request
+// targets from the X-Docker-Host header (falling back to
+// utils.DefaultHostName) and stores the matching *client.Client in the gin
+// context, so docker routes can manage any registered host instead of a
+// single package-global daemon.
+func DockerHostMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostName := c.GetHeader("X-Docker-Host")
+		if hostName == "" {
+			hostName = utils.DefaultHostName
+		}
+
+		cli, err := utils.Registry().Client(hostName)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Set(DockerHostKey, cli)
+		c.Set(DockerHostNameKey, hostName)
+		c.Next()
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"os"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// DB is the process-wide database handle, initialized by ConnectDatabase.
+var DB *gorm.DB
+
+// ConnectDatabase opens the sqlite database named by the DB_NAME env var
+// (falling back to "gorm.db") and migrates every model the API persists.
+func ConnectDatabase() error {
+	dbName := os.Getenv("DB_NAME")
+	if dbName == "" {
+		dbName = "gorm.db"
+	}
+
+	db, err := gorm.Open(sqlite.Open(dbName), &gorm.Config{})
+	if err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(&User{}, &DockerHost{}); err != nil {
+		return err
+	}
+
+	DB = db
+	return nil
+}
@@ -0,0 +1,36 @@
+package models
+
+import "gorm.io/gorm"
+
+// DockerHost is a daemon registered with the dashboard so it can manage more
+// than one Docker host. TLS material is stored as a filesystem path (the
+// same client.crt/client.key/ca.crt layout ConnectToDocker already expects)
+// rather than embedding the certificates themselves.
+type DockerHost struct {
+	gorm.Model
+	Name     string `gorm:"size:255;not null;unique" json:"name"`
+	Address  string `gorm:"size:255;not null" json:"address"`
+	CertPath string `gorm:"size:255;not null" json:"certPath"`
+}
+
+// SaveHost persists a new DockerHost.
+func (host *DockerHost) SaveHost() (*DockerHost, error) {
+	if err := DB.Create(&host).Error; err != nil {
+		return &DockerHost{}, err
+	}
+	return host, nil
+}
+
+// ListHosts returns every registered DockerHost.
+func ListHosts() ([]DockerHost, error) {
+	var hosts []DockerHost
+	if err := DB.Find(&hosts).Error; err != nil {
+		return nil, err
+	}
+	return hosts, nil
+}
+
+// DeleteHostByName removes the DockerHost registered under name.
+func DeleteHostByName(name string) error {
+	return DB.Where("name = ?", name).Delete(&DockerHost{}).Error
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	"github.com/voidchef/devops/errdefs"
+)
+
+// PortSpec describes a single container port to expose, optionally bound to
+// a host port.
+type PortSpec struct {
+	ContainerPort string `json:"containerPort" binding:"required"`
+	Protocol      string `json:"protocol"`
+	HostIP        string `json:"hostIP"`
+	HostPort      string `json:"hostPort"`
+}
+
+// MountSpec describes a bind mount or named volume to attach.
+type MountSpec struct {
+	Type     string `json:"type" binding:"required"` // "bind" or "volume"
+	Source   string `json:"source" binding:"required"`
+	Target   string `json:"target" binding:"required"`
+	ReadOnly bool   `json:"readOnly"`
+}
+
+// ContainerSpec is the JSON shape accepted by POST /api/docker/containers.
+type ContainerSpec struct {
+	Image         string            `json:"image" binding:"required"`
+	Name          string            `json:"name"`
+	Env           []string          `json:"env"`
+	Cmd           []string          `json:"cmd"`
+	Ports         []PortSpec        `json:"ports"`
+	Mounts        []MountSpec       `json:"mounts"`
+	NetworkMode   string            `json:"networkMode"`
+	RestartPolicy string            `json:"restartPolicy"` // "no", "always", "on-failure", "unless-stopped"
+	Labels        map[string]string `json:"labels"`
+	Memory        int64             `json:"memory"`   // bytes
+	NanoCPUs      int64             `json:"nanoCPUs"` // 1e9 NanoCPUs == 1 CPU
+}
+
+// CreateContainer builds container.Config/HostConfig/NetworkingConfig from
+// spec and creates (but does not start) the container. Use
+// StartContainerByID to run it.
+func CreateContainer(cli *client.Client, spec ContainerSpec) (string, error) {
+	exposedPorts, portBindings, err := buildPorts(spec.Ports)
+	if err != nil {
+		return "", err
+	}
+
+	mounts, err := buildMounts(spec.Mounts)
+	if err != nil {
+		return "", err
+	}
+
+	config := &container.Config{
+		Image:        spec.Image,
+		Env:          spec.Env,
+		Cmd:          spec.Cmd,
+		ExposedPorts: exposedPorts,
+		Labels:       spec.Labels,
+	}
+
+	hostConfig := &container.HostConfig{
+		PortBindings: portBindings,
+		Mounts:       mounts,
+		NetworkMode:  containerNetworkMode(spec.NetworkMode),
+		RestartPolicy: container.RestartPolicy{
+			Name: restartPolicyName(spec.RestartPolicy),
+		},
+		Resources: container.Resources{
+			Memory:   spec.Memory,
+			NanoCPUs: spec.NanoCPUs,
+		},
+	}
+
+	resp, err := cli.ContainerCreate(ctx, config, hostConfig, &network.NetworkingConfig{}, nil, spec.Name)
+	if err != nil {
+		return "", errdefs.FromDockerError(fmt.Errorf("failed to create container from image %s: %w", spec.Image, err))
+	}
+
+	return resp.ID, nil
+}
+
+func buildPorts(specs []PortSpec) (nat.PortSet, nat.PortMap, error) {
+	exposedPorts := nat.PortSet{}
+	portBindings := nat.PortMap{}
+
+	for _, p := range specs {
+		protocol := p.Protocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+
+		port, err := nat.NewPort(protocol, p.ContainerPort)
+		if err != nil {
+			return nil, nil, errdefs.InvalidParameter(fmt.Errorf("invalid port %s/%s: %w", p.ContainerPort, protocol, err))
+		}
+
+		exposedPorts[port] = struct{}{}
+		if p.HostPort != "" {
+			portBindings[port] = append(portBindings[port], nat.PortBinding{
+				HostIP:   p.HostIP,
+				HostPort: p.HostPort,
+			})
+		}
+	}
+
+	return exposedPorts, portBindings, nil
+}
+
+func buildMounts(specs []MountSpec) ([]mount.Mount, error) {
+	mounts := make([]mount.Mount, 0, len(specs))
+
+	for _, m := range specs {
+		var mountType mount.Type
+		switch m.Type {
+		case "bind":
+			mountType = mount.TypeBind
+		case "volume":
+			mountType = mount.TypeVolume
+		default:
+			return nil, errdefs.InvalidParameter(fmt.Errorf("unsupported mount type %q (must be bind or volume)", m.Type))
+		}
+
+		mounts = append(mounts, mount.Mount{
+			Type:     mountType,
+			Source:   m.Source,
+			Target:   m.Target,
+			ReadOnly: m.ReadOnly,
+		})
+	}
+
+	return mounts, nil
+}
+
+func containerNetworkMode(mode string) container.NetworkMode {
+	if mode == "" {
+		return container.NetworkMode("default")
+	}
+	return container.NetworkMode(mode)
+}
+
+func restartPolicyName(policy string) container.RestartPolicyMode {
+	switch policy {
+	case "always", "on-failure", "unless-stopped":
+		return container.RestartPolicyMode(policy)
+	default:
+		return container.RestartPolicyMode("no")
+	}
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/voidchef/devops/errdefs"
+)
+
+// GetContainerLogs wraps cli.ContainerLogs. When opts.Follow is true the
+// returned reader stays open and new log lines arrive as the container
+// produces them; the caller is responsible for closing it. The stream is
+// multiplexed per Docker's wire format (an 8-byte header per frame) unless
+// the container was started with a TTY, in which case it is raw text —
+// callers should demultiplex with stdcopy.StdCopy when Tty is false.
+func GetContainerLogs(cli *client.Client, containerID string, opts types.ContainerLogsOptions) (io.ReadCloser, error) {
+	logs, err := cli.ContainerLogs(ctx, containerID, opts)
+	if err != nil {
+		return nil, errdefs.FromDockerError(fmt.Errorf("failed to get logs for container %s: %w", containerID, err))
+	}
+
+	return logs, nil
+}
+
+// ContainerHasTTY reports whether containerID was started with a TTY, since
+// that determines whether its log stream is already demultiplexed.
+func ContainerHasTTY(cli *client.Client, containerID string) (bool, error) {
+	info, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return false, errdefs.FromDockerError(fmt.Errorf("failed to inspect container %s: %w", containerID, err))
+	}
+
+	return info.Config.Tty, nil
+}
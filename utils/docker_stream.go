@@ -0,0 +1,373 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/voidchef/devops/errdefs"
+)
+
+// containerStatsHub fans the single `cli.ContainerStats` stream for one
+// container out to any number of subscribers (e.g. several dashboards with
+// the same container open).
+type containerStatsHub struct {
+	mu          sync.Mutex
+	owner       *StatsHub
+	key         string
+	hostName    string
+	containerID string
+	subscribers map[chan types.StatsJSON]struct{}
+	cancel      func()
+	closed      bool
+}
+
+// StatsHub multiplexes Docker stats streams, one containerStatsHub per
+// (host, container) pair, so a given daemon is only asked for a given
+// container's stream once no matter how many dashboards are watching it.
+type StatsHub struct {
+	mu   sync.Mutex
+	hubs map[string]*containerStatsHub
+}
+
+// NewStatsHub creates an empty StatsHub.
+func NewStatsHub() *StatsHub {
+	return &StatsHub{hubs: make(map[string]*containerStatsHub)}
+}
+
+// dockerStatsHub is the process-wide hub used by the stats WebSocket route.
+var dockerStatsHub = NewStatsHub()
+
+// DefaultStatsHub returns the process-wide stats hub.
+func DefaultStatsHub() *StatsHub {
+	return dockerStatsHub
+}
+
+func statsHubKey(hostName string, containerID string) string {
+	return hostName + "|" + containerID
+}
+
+// Subscribe returns a channel that receives a types.StatsJSON frame every
+// time hostName's daemon reports one for containerID, and an unsubscribe
+// func that must be called when the caller is done reading. The underlying
+// `cli.ContainerStats` stream is started lazily on the first subscriber and
+// torn down once the last one leaves.
+func (h *StatsHub) Subscribe(cli *client.Client, hostName string, containerID string) (<-chan types.StatsJSON, func(), error) {
+	// Resolve to the canonical full container ID so the hub key matches the
+	// one EventsHub uses (Docker events always carry the full ID in
+	// msg.Actor.ID), even when the caller passed a name or short ID.
+	info, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, nil, errdefs.FromDockerError(fmt.Errorf("failed to resolve container %s: %w", containerID, err))
+	}
+	containerID = info.ID
+
+	key := statsHubKey(hostName, containerID)
+
+	h.mu.Lock()
+	hub, ok := h.hubs[key]
+	if !ok {
+		hub = &containerStatsHub{
+			owner:       h,
+			key:         key,
+			hostName:    hostName,
+			containerID: containerID,
+			subscribers: make(map[chan types.StatsJSON]struct{}),
+		}
+		h.hubs[key] = hub
+	}
+	h.mu.Unlock()
+
+	ch := make(chan types.StatsJSON, 8)
+
+	hub.mu.Lock()
+	firstSubscriber := len(hub.subscribers) == 0
+	hub.subscribers[ch] = struct{}{}
+	hub.mu.Unlock()
+
+	if firstSubscriber {
+		if err := hub.start(cli); err != nil {
+			hub.mu.Lock()
+			delete(hub.subscribers, ch)
+			hub.mu.Unlock()
+			return nil, nil, err
+		}
+	}
+
+	unsubscribe := func() {
+		hub.mu.Lock()
+		delete(hub.subscribers, ch)
+		empty := len(hub.subscribers) == 0
+		hub.mu.Unlock()
+
+		if empty {
+			h.mu.Lock()
+			delete(h.hubs, key)
+			h.mu.Unlock()
+			hub.stop()
+		}
+	}
+
+	return ch, unsubscribe, nil
+}
+
+// start begins reading the daemon's streaming stats response in a
+// background goroutine and broadcasts each decoded frame to subscribers.
+func (hub *containerStatsHub) start(cli *client.Client) error {
+	streamCtx, cancel := newCancelableContext()
+	hub.cancel = cancel
+
+	resp, err := cli.ContainerStats(streamCtx, hub.containerID, true)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to start stats stream for container %s: %v", hub.containerID, err)
+	}
+
+	go func() {
+		defer resp.Body.Close()
+
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var frame types.StatsJSON
+			if err := decoder.Decode(&frame); err != nil {
+				if err != io.EOF {
+					fmt.Printf("stats stream for container %s ended: %v\n", hub.containerID, err)
+				}
+				// The daemon closes the stream when the container stops or
+				// is removed (or the request context is canceled). Either
+				// way, no more frames are coming, so tear the hub down
+				// ourselves instead of relying on an events subscriber
+				// (which may not exist) to do it.
+				hub.close()
+				return
+			}
+			hub.broadcast(frame)
+		}
+	}()
+
+	return nil
+}
+
+func (hub *containerStatsHub) stop() {
+	if hub.cancel != nil {
+		hub.cancel()
+	}
+}
+
+func (hub *containerStatsHub) broadcast(frame types.StatsJSON) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if hub.closed {
+		return
+	}
+
+	for ch := range hub.subscribers {
+		select {
+		case ch <- frame:
+		default:
+			// Slow subscriber, drop the frame rather than block the stream.
+		}
+	}
+}
+
+// CPUPercent computes the CPU usage percentage the way `docker stats` does,
+// using the delta between two consecutive samples rather than the
+// cumulative counters in a single snapshot.
+func CPUPercent(previous, current *types.StatsJSON) float64 {
+	if previous == nil || current == nil {
+		return 0
+	}
+
+	return cpuPercentDelta(previous.CPUStats, current.CPUStats)
+}
+
+// CPUPercentFromSnapshot computes the CPU usage percentage from a single
+// one-shot StatsJSON response, using its embedded PreCPUStats as the earlier
+// sample. This lets callers that only take one snapshot (rather than reading
+// a stream) still get a proper two-sample delta instead of the bogus
+// cumulative-counter ratio.
+func CPUPercentFromSnapshot(stats *types.StatsJSON) float64 {
+	if stats == nil {
+		return 0
+	}
+
+	return cpuPercentDelta(stats.PreCPUStats, stats.CPUStats)
+}
+
+func cpuPercentDelta(previous, current types.CPUStats) float64 {
+	cpuDelta := float64(current.CPUUsage.TotalUsage) - float64(previous.CPUUsage.TotalUsage)
+	systemDelta := float64(current.SystemUsage) - float64(previous.SystemUsage)
+
+	onlineCPUs := float64(current.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(current.CPUUsage.PercpuUsage))
+	}
+	if onlineCPUs == 0 {
+		onlineCPUs = 1
+	}
+
+	if systemDelta <= 0 || cpuDelta <= 0 {
+		return 0
+	}
+
+	return (cpuDelta / systemDelta) * onlineCPUs * 100
+}
+
+// EventsHub fans out a single daemon-wide `cli.Events` stream to any number
+// of subscribers, so every connected dashboard sees the same event feed
+// without opening its own connection to the daemon.
+type EventsHub struct {
+	hostName    string
+	mu          sync.Mutex
+	subscribers map[chan events.Message]struct{}
+	started     bool
+	cancel      func()
+}
+
+// eventsHubs holds one EventsHub per registered Docker host.
+var (
+	eventsHubsMu sync.Mutex
+	eventsHubs   = make(map[string]*EventsHub)
+)
+
+// EventsHubFor returns the EventsHub for hostName, creating it if necessary.
+func EventsHubFor(hostName string) *EventsHub {
+	eventsHubsMu.Lock()
+	defer eventsHubsMu.Unlock()
+
+	hub, ok := eventsHubs[hostName]
+	if !ok {
+		hub = &EventsHub{hostName: hostName, subscribers: make(map[chan events.Message]struct{})}
+		eventsHubs[hostName] = hub
+	}
+
+	return hub
+}
+
+// Subscribe returns a channel of Docker events and an unsubscribe func. The
+// `cli.Events` stream is started on the first subscriber and stopped once
+// the last subscriber leaves.
+func (h *EventsHub) Subscribe(cli *client.Client) (<-chan events.Message, func()) {
+	h.mu.Lock()
+	ch := make(chan events.Message, 16)
+	h.subscribers[ch] = struct{}{}
+	firstSubscriber := !h.started
+	if firstSubscriber {
+		h.started = true
+	}
+	h.mu.Unlock()
+
+	if firstSubscriber {
+		h.start(cli)
+	}
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		delete(h.subscribers, ch)
+		empty := len(h.subscribers) == 0
+		if empty {
+			h.started = false
+		}
+		h.mu.Unlock()
+
+		if empty && h.cancel != nil {
+			h.cancel()
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+func (h *EventsHub) start(cli *client.Client) {
+	streamCtx, cancel := newCancelableContext()
+	h.cancel = cancel
+
+	msgCh, errCh := cli.Events(streamCtx, types.EventsOptions{Filters: filters.NewArgs()})
+
+	go func() {
+		for {
+			select {
+			case msg, ok := <-msgCh:
+				if !ok {
+					return
+				}
+				h.broadcast(msg)
+
+				// A stopped or removed container has nothing left to stream
+				// stats for, so tear down its stats hub proactively.
+				if msg.Type == events.ContainerEventType && (msg.Action == "stop" || msg.Action == "die" || msg.Action == "destroy") {
+					dockerStatsHub.closeContainer(h.hostName, msg.Actor.ID)
+				}
+			case err, ok := <-errCh:
+				if !ok || err == nil {
+					continue
+				}
+				if err != io.EOF {
+					fmt.Printf("docker events stream for host %s ended: %v\n", h.hostName, err)
+				}
+				return
+			}
+		}
+	}()
+}
+
+func (h *EventsHub) broadcast(msg events.Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// closeContainer tears down the stats hub for (hostName, containerID), if
+// one is running, and closes every subscriber channel so their WebSocket
+// handlers unwind.
+func (h *StatsHub) closeContainer(hostName string, containerID string) {
+	key := statsHubKey(hostName, containerID)
+
+	h.mu.Lock()
+	hub, ok := h.hubs[key]
+	h.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	hub.close()
+}
+
+// close marks the hub closed, unregisters it from the owning StatsHub (if
+// it's still the hub registered under its key — a concurrent Subscribe may
+// already have replaced it), stops the underlying stream, and closes every
+// subscriber channel so blocked WebSocket readers unblock. Safe to call more
+// than once and from multiple goroutines (the stats stream's own EOF and an
+// EventsHub-triggered teardown can race).
+func (hub *containerStatsHub) close() {
+	hub.owner.mu.Lock()
+	if hub.owner.hubs[hub.key] == hub {
+		delete(hub.owner.hubs, hub.key)
+	}
+	hub.owner.mu.Unlock()
+
+	hub.stop()
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	if hub.closed {
+		return
+	}
+	hub.closed = true
+	for ch := range hub.subscribers {
+		close(ch)
+	}
+}
@@ -0,0 +1,217 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/voidchef/devops/errdefs"
+)
+
+// Default time allowed for a rolling update's health check and graceful
+// stop, tunable per call via UpdateOptions.
+const (
+	defaultUpdateHealthCheckTimeout = 30 * time.Second
+	defaultUpdateStopTimeout        = 10 * time.Second
+)
+
+// UpdateOptions controls how UpdateContainerByID performs the rollout.
+type UpdateOptions struct {
+	// HealthCheckTimeout bounds how long to wait for the new container to
+	// report healthy (or, absent a healthcheck, to still be running).
+	HealthCheckTimeout time.Duration
+	// StopTimeout bounds how long the old container is given to stop
+	// gracefully before the update proceeds.
+	StopTimeout time.Duration
+}
+
+// UpdateResult describes what a call to UpdateContainerByID actually did,
+// so the controller can surface it to the UI instead of a bare "success".
+type UpdateResult struct {
+	Step        string `json:"step"`
+	OldImageID  string `json:"oldImageID"`
+	NewImageID  string `json:"newImageID"`
+	OldImage    string `json:"oldImage"`
+	NewImage    string `json:"newImage"`
+	ContainerID string `json:"containerID"`
+}
+
+const (
+	updateStepNoop       = "no-op: image unchanged"
+	updateStepRolledBack = "rolled back: new container failed health check"
+	updateStepCompleted  = "completed"
+)
+
+// UpdateContainerByID performs a zero-downtime update of containerID to the
+// latest version of its image:
+//
+//  1. inspect the current image digest, pull, and compare digests to skip
+//     no-op updates;
+//  2. rename the old container out of the way;
+//  3. stop it gracefully;
+//  4. start the replacement and health-check it;
+//  5. on failure, remove the replacement and restore the old container;
+//  6. on success, remove the old container.
+func UpdateContainerByID(cli *client.Client, containerID string, opts UpdateOptions) (*UpdateResult, error) {
+	if opts.HealthCheckTimeout <= 0 {
+		opts.HealthCheckTimeout = defaultUpdateHealthCheckTimeout
+	}
+	if opts.StopTimeout <= 0 {
+		opts.StopTimeout = defaultUpdateStopTimeout
+	}
+
+	containerInfo, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, errdefs.FromDockerError(fmt.Errorf("failed to get container %s info: %w", containerID, err))
+	}
+
+	oldImageID := containerInfo.Image
+	imageRef := containerInfo.Config.Image
+
+	oldImageInspect, _, err := cli.ImageInspectWithRaw(ctx, oldImageID)
+	if err != nil {
+		return nil, errdefs.FromDockerError(fmt.Errorf("failed to inspect current image %s: %w", oldImageID, err))
+	}
+
+	out, err := cli.ImagePull(ctx, imageRef, types.ImagePullOptions{})
+	if err != nil {
+		return nil, errdefs.FromDockerError(fmt.Errorf("failed to pull image %s: %w", imageRef, err))
+	}
+	defer out.Close()
+	io.Copy(ioutil.Discard, out)
+
+	newImageInspect, _, err := cli.ImageInspectWithRaw(ctx, imageRef)
+	if err != nil {
+		return nil, errdefs.FromDockerError(fmt.Errorf("failed to inspect pulled image %s: %w", imageRef, err))
+	}
+
+	result := &UpdateResult{
+		ContainerID: containerID,
+		OldImage:    imageRef,
+		NewImage:    imageRef,
+		OldImageID:  oldImageInspect.ID,
+		NewImageID:  newImageInspect.ID,
+	}
+
+	if oldImageInspect.ID == newImageInspect.ID {
+		result.Step = updateStepNoop
+		return result, nil
+	}
+
+	oldName := containerInfo.Name
+	renamedName := fmt.Sprintf("%s-old-%d", trimSlash(oldName), time.Now().Unix())
+
+	if err := cli.ContainerRename(ctx, containerID, trimSlash(renamedName)); err != nil {
+		return nil, errdefs.FromDockerError(fmt.Errorf("failed to rename old container %s: %w", containerID, err))
+	}
+
+	stopTimeoutSeconds := int(opts.StopTimeout.Seconds())
+	if err := cli.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &stopTimeoutSeconds}); err != nil {
+		// Best effort: continue with the rollout even if the old
+		// container was already stopped or refused to stop in time.
+		fmt.Printf("warning: failed to stop old container %s gracefully: %v\n", containerID, err)
+	}
+
+	networkConfig := &network.NetworkingConfig{
+		EndpointsConfig: containerInfo.NetworkSettings.Networks,
+	}
+
+	resp, err := cli.ContainerCreate(ctx, containerInfo.Config, containerInfo.HostConfig, networkConfig, nil, trimSlash(oldName))
+	if err != nil {
+		rollback(cli, containerID, renamedName, oldName)
+		return nil, errdefs.FromDockerError(fmt.Errorf("failed to create replacement container from image %s: %w", imageRef, err))
+	}
+
+	if err := StartContainerByID(cli, resp.ID); err != nil {
+		cli.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{Force: true})
+		rollback(cli, containerID, renamedName, oldName)
+		return nil, fmt.Errorf("failed to start replacement container: %v", err)
+	}
+
+	if err := waitForHealthy(cli, resp.ID, opts.HealthCheckTimeout); err != nil {
+		cli.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{Force: true})
+		rollback(cli, containerID, renamedName, oldName)
+		result.Step = updateStepRolledBack
+		return result, fmt.Errorf("replacement container failed health check, rolled back: %v", err)
+	}
+
+	// Success: the old container (still under its renamed identity) can go.
+	if err := DeleteContainerByID(cli, containerID); err != nil {
+		fmt.Printf("warning: failed to remove old container %s: %v\n", containerID, err)
+	}
+
+	result.ContainerID = resp.ID
+	result.Step = updateStepCompleted
+	return result, nil
+}
+
+// rollback restores the old container to its original name and restarts it
+// after a failed update attempt.
+func rollback(cli *client.Client, oldContainerID string, renamedName string, originalName string) {
+	if err := cli.ContainerRename(ctx, oldContainerID, trimSlash(originalName)); err != nil {
+		fmt.Printf("warning: failed to restore old container name %s: %v\n", originalName, err)
+		return
+	}
+
+	if err := StartContainerByID(cli, oldContainerID); err != nil {
+		fmt.Printf("warning: failed to restart old container %s after rollback: %v\n", oldContainerID, err)
+	}
+}
+
+// waitForHealthy polls the container's health status if a healthcheck is
+// defined, otherwise it just verifies the container is still running after
+// the timeout elapses.
+func waitForHealthy(cli *client.Client, containerID string, timeout time.Duration) error {
+	info, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return errdefs.FromDockerError(fmt.Errorf("failed to inspect container %s: %w", containerID, err))
+	}
+
+	hasHealthCheck := info.Config.Healthcheck != nil && len(info.Config.Healthcheck.Test) > 0
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		info, err := cli.ContainerInspect(ctx, containerID)
+		if err != nil {
+			return errdefs.FromDockerError(fmt.Errorf("failed to inspect container %s: %w", containerID, err))
+		}
+
+		if !info.State.Running {
+			return fmt.Errorf("container %s is not running (status: %s)", containerID, info.State.Status)
+		}
+
+		if hasHealthCheck {
+			if info.State.Health != nil {
+				switch info.State.Health.Status {
+				case types.Healthy:
+					return nil
+				case types.Unhealthy:
+					return fmt.Errorf("container %s reported unhealthy", containerID)
+				}
+			}
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	if hasHealthCheck {
+		return fmt.Errorf("container %s did not become healthy within %s", containerID, timeout)
+	}
+
+	// No healthcheck defined: reaching the deadline while still running is
+	// the success condition.
+	return nil
+}
+
+// trimSlash strips the leading "/" Docker puts on container names.
+func trimSlash(name string) string {
+	if len(name) > 0 && name[0] == '/' {
+		return name[1:]
+	}
+	return name
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/voidchef/devops/errdefs"
+)
+
+// ListImages lists the images stored on the Docker daemon.
+func ListImages(cli *client.Client) ([]types.ImageSummary, error) {
+	images, err := cli.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return nil, errdefs.FromDockerError(fmt.Errorf("failed to list images: %w", err))
+	}
+
+	return images, nil
+}
+
+// DecodeRegistryAuth decodes the base64-encoded `X-Registry-Auth` header
+// value into a types.AuthConfig and re-encodes it the way the Docker client
+// expects it on the wire, so callers can pass it straight through to
+// PullImage.
+func DecodeRegistryAuth(header string) (string, error) {
+	if header == "" {
+		return "", nil
+	}
+
+	raw, err := base64.URLEncoding.DecodeString(header)
+	if err != nil {
+		// Some clients send standard base64 instead of URL-safe base64.
+		raw, err = base64.StdEncoding.DecodeString(header)
+		if err != nil {
+			return "", errdefs.InvalidParameter(fmt.Errorf("failed to decode X-Registry-Auth header: %w", err))
+		}
+	}
+
+	var authConfig types.AuthConfig
+	if err := json.Unmarshal(raw, &authConfig); err != nil {
+		return "", errdefs.InvalidParameter(fmt.Errorf("failed to parse X-Registry-Auth header: %w", err))
+	}
+
+	encoded, err := json.Marshal(authConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to re-encode registry auth: %v", err)
+	}
+
+	return base64.URLEncoding.EncodeToString(encoded), nil
+}
+
+// PullImage pulls ref from its registry and returns the daemon's raw,
+// newline-delimited JSON progress stream. Callers (e.g. the SSE controller)
+// are responsible for decoding each line with jsonmessage and closing the
+// returned reader when done.
+func PullImage(cli *client.Client, ref string, registryAuth string) (io.ReadCloser, error) {
+	out, err := cli.ImagePull(ctx, ref, types.ImagePullOptions{
+		RegistryAuth: registryAuth,
+	})
+	if err != nil {
+		return nil, errdefs.FromDockerError(fmt.Errorf("failed to pull image %s: %w", ref, err))
+	}
+
+	return out, nil
+}
+
+// BuildImage builds an image from buildContext (a tar stream) and returns the
+// daemon's raw JSON progress stream, same shape as PullImage.
+func BuildImage(cli *client.Client, buildContext io.Reader, options types.ImageBuildOptions) (io.ReadCloser, error) {
+	resp, err := cli.ImageBuild(ctx, buildContext, options)
+	if err != nil {
+		return nil, errdefs.FromDockerError(fmt.Errorf("failed to build image: %w", err))
+	}
+
+	return resp.Body, nil
+}
+
+// TagImage tags the image identified by source (ID or ref) with target.
+func TagImage(cli *client.Client, source string, target string) error {
+	if err := cli.ImageTag(ctx, source, target); err != nil {
+		return errdefs.FromDockerError(fmt.Errorf("failed to tag image %s as %s: %w", source, target, err))
+	}
+
+	return nil
+}
+
+// RemoveImage removes the image identified by imageID. Containers using the
+// image are not stopped; pass force=true to remove it anyway.
+func RemoveImage(cli *client.Client, imageID string, force bool) ([]types.ImageDeleteResponseItem, error) {
+	removed, err := cli.ImageRemove(ctx, imageID, types.ImageRemoveOptions{
+		Force:         force,
+		PruneChildren: true,
+	})
+	if err != nil {
+		return nil, errdefs.FromDockerError(fmt.Errorf("failed to remove image %s: %w", imageID, err))
+	}
+
+	return removed, nil
+}
+
+// PruneImages removes unused images and returns how much disk space was
+// reclaimed.
+func PruneImages(cli *client.Client) (types.ImagesPruneReport, error) {
+	report, err := cli.ImagesPrune(ctx, filters.NewArgs())
+	if err != nil {
+		return types.ImagesPruneReport{}, errdefs.FromDockerError(fmt.Errorf("failed to prune images: %w", err))
+	}
+
+	return report, nil
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/voidchef/devops/errdefs"
+)
+
+// ListNetworks lists the networks on the Docker daemon.
+func ListNetworks(cli *client.Client) ([]types.NetworkResource, error) {
+	networks, err := cli.NetworkList(ctx, types.NetworkListOptions{Filters: filters.NewArgs()})
+	if err != nil {
+		return nil, errdefs.FromDockerError(fmt.Errorf("failed to list networks: %w", err))
+	}
+
+	return networks, nil
+}
+
+// CreateNetwork creates a network with the given driver.
+func CreateNetwork(cli *client.Client, name string, driver string) (string, error) {
+	if driver == "" {
+		driver = "bridge"
+	}
+
+	resp, err := cli.NetworkCreate(ctx, name, types.NetworkCreate{Driver: driver})
+	if err != nil {
+		return "", errdefs.FromDockerError(fmt.Errorf("failed to create network %s: %w", name, err))
+	}
+
+	return resp.ID, nil
+}
+
+// RemoveNetwork removes the network identified by networkID.
+func RemoveNetwork(cli *client.Client, networkID string) error {
+	if err := cli.NetworkRemove(ctx, networkID); err != nil {
+		return errdefs.FromDockerError(fmt.Errorf("failed to remove network %s: %w", networkID, err))
+	}
+
+	return nil
+}
+
+// ConnectNetwork attaches containerID to networkID.
+func ConnectNetwork(cli *client.Client, networkID string, containerID string) error {
+	if err := cli.NetworkConnect(ctx, networkID, containerID, &network.EndpointSettings{}); err != nil {
+		return errdefs.FromDockerError(fmt.Errorf("failed to connect container %s to network %s: %w", containerID, networkID, err))
+	}
+
+	return nil
+}
+
+// DisconnectNetwork detaches containerID from networkID. Pass force=true to
+// detach it even if the daemon thinks it's unreachable.
+func DisconnectNetwork(cli *client.Client, networkID string, containerID string, force bool) error {
+	if err := cli.NetworkDisconnect(ctx, networkID, containerID, force); err != nil {
+		return errdefs.FromDockerError(fmt.Errorf("failed to disconnect container %s from network %s: %w", containerID, networkID, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// DefaultHostName is the registry key for the daemon configured via
+// CERT_PATH/DOCKER_HOST/DOCKER_PORT env vars, the host ConnectToDocker has
+// always dialed. Requests that don't set X-Docker-Host fall back to it.
+const DefaultHostName = "default"
+
+// DockerHostConfig describes one Docker daemon to dial over TCP+TLS.
+type DockerHostConfig struct {
+	Name     string
+	Address  string // host:port, e.g. "10.0.0.5:2376"
+	CertPath string // directory containing ca.crt, client.crt, client.key
+}
+
+// DockerHostRegistry holds one *client.Client per registered daemon, plus
+// the last health-check result for each, so the dashboard can manage a
+// fleet of hosts instead of a single one.
+type DockerHostRegistry struct {
+	mu      sync.RWMutex
+	clients map[string]*client.Client
+	up      map[string]bool
+}
+
+var hostRegistry = &DockerHostRegistry{
+	clients: make(map[string]*client.Client),
+	up:      make(map[string]bool),
+}
+
+// Registry returns the process-wide Docker host registry.
+func Registry() *DockerHostRegistry {
+	return hostRegistry
+}
+
+// newTLSClient dials cfg.Address over TCP+TLS using the client certificate,
+// key and CA in cfg.CertPath.
+func newTLSClient(cfg DockerHostConfig) (*client.Client, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(cfg.CertPath, "client.crt"), filepath.Join(cfg.CertPath, "client.key"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate and key for host %s: %v", cfg.Name, err)
+	}
+
+	caCert, err := ioutil.ReadFile(filepath.Join(cfg.CertPath, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate for host %s: %v", cfg.Name, err)
+	}
+	caCertPool := x509.NewCertPool()
+	caCertPool.AppendCertsFromPEM(caCert)
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      caCertPool,
+	}
+	tlsConfig.BuildNameToCertificate()
+
+	// Bound only the wait for response headers: an overall client Timeout
+	// would also cut off long-lived bodies (events, stats, followed logs).
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:       tlsConfig,
+			ResponseHeaderTimeout: time.Second * 10,
+		},
+	}
+
+	return client.NewClientWithOpts(
+		client.WithHTTPClient(httpClient),
+		client.WithAPIVersionNegotiation(),
+		client.WithHost("tcp://"+cfg.Address),
+		client.WithTLSClientConfig(filepath.Join(cfg.CertPath, "ca.crt"), filepath.Join(cfg.CertPath, "client.crt"), filepath.Join(cfg.CertPath, "client.key")),
+	)
+}
+
+// Register dials cfg and, once it responds to a ping, adds it to the
+// registry under cfg.Name, replacing any existing client of the same name.
+func (r *DockerHostRegistry) Register(cfg DockerHostConfig) error {
+	c, err := newTLSClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping docker host %s: %v", cfg.Name, err)
+	}
+
+	r.mu.Lock()
+	if old, ok := r.clients[cfg.Name]; ok {
+		old.Close()
+	}
+	r.clients[cfg.Name] = c
+	r.up[cfg.Name] = true
+	r.mu.Unlock()
+
+	return nil
+}
+
+// CloseAll closes every registered host's *client.Client and empties the
+// registry, for use during graceful shutdown.
+func (r *DockerHostRegistry) CloseAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, c := range r.clients {
+		c.Close()
+		delete(r.clients, name)
+		delete(r.up, name)
+	}
+}
+
+// Unregister closes and removes the named host.
+func (r *DockerHostRegistry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.clients[name]; ok {
+		c.Close()
+		delete(r.clients, name)
+		delete(r.up, name)
+	}
+}
+
+// Client returns the *client.Client registered under name.
+func (r *DockerHostRegistry) Client(name string) (*client.Client, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.clients[name]
+	if !ok {
+		return nil, fmt.Errorf("no docker host registered as %q", name)
+	}
+
+	return c, nil
+}
+
+// Status is the last known reachability of a registered host.
+type Status struct {
+	Name string `json:"name"`
+	Up   bool   `json:"up"`
+}
+
+// Statuses lists every registered host and its last health-check result.
+func (r *DockerHostRegistry) Statuses() []Status {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(r.clients))
+	for name := range r.clients {
+		statuses = append(statuses, Status{Name: name, Up: r.up[name]})
+	}
+	return statuses
+}
+
+// StartHealthChecks pings every registered host on interval, recording
+// whether each one is reachable, until stop is closed.
+func (r *DockerHostRegistry) StartHealthChecks(interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				r.pingAll()
+			}
+		}
+	}()
+}
+
+func (r *DockerHostRegistry) pingAll() {
+	r.mu.RLock()
+	snapshot := make(map[string]*client.Client, len(r.clients))
+	for name, c := range r.clients {
+		snapshot[name] = c
+	}
+	r.mu.RUnlock()
+
+	for name, c := range snapshot {
+		_, err := c.Ping(ctx)
+
+		r.mu.Lock()
+		r.up[name] = err == nil
+		r.mu.Unlock()
+	}
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types/filters"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/voidchef/devops/errdefs"
+)
+
+// ListVolumes lists the volumes on the Docker daemon.
+func ListVolumes(cli *client.Client) ([]*volumetypes.Volume, error) {
+	resp, err := cli.VolumeList(ctx, filters.NewArgs())
+	if err != nil {
+		return nil, errdefs.FromDockerError(fmt.Errorf("failed to list volumes: %w", err))
+	}
+
+	return resp.Volumes, nil
+}
+
+// CreateVolume creates a named volume with the given driver and labels.
+func CreateVolume(cli *client.Client, name string, driver string, labels map[string]string) (volumetypes.Volume, error) {
+	if driver == "" {
+		driver = "local"
+	}
+
+	vol, err := cli.VolumeCreate(ctx, volumetypes.CreateOptions{
+		Name:   name,
+		Driver: driver,
+		Labels: labels,
+	})
+	if err != nil {
+		return volumetypes.Volume{}, errdefs.FromDockerError(fmt.Errorf("failed to create volume %s: %w", name, err))
+	}
+
+	return vol, nil
+}
+
+// RemoveVolume removes the named volume. Pass force=true to remove it even
+// if it's in use.
+func RemoveVolume(cli *client.Client, name string, force bool) error {
+	if err := cli.VolumeRemove(ctx, name, force); err != nil {
+		return errdefs.FromDockerError(fmt.Errorf("failed to remove volume %s: %w", name, err))
+	}
+
+	return nil
+}